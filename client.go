@@ -0,0 +1,255 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/ericchiang/k8s/watch/versioned"
+)
+
+// codec marshals and unmarshals request/response bodies and advertises the
+// content type they should be sent/requested as.
+type codec interface {
+	ContentType() string
+	Marshal(obj interface{}) ([]byte, error)
+	Unmarshal(data []byte, obj interface{}) error
+}
+
+type jsonCodecType struct{}
+
+func (jsonCodecType) ContentType() string                     { return "application/json" }
+func (jsonCodecType) Marshal(obj interface{}) ([]byte, error) { return json.Marshal(obj) }
+func (jsonCodecType) Unmarshal(data []byte, obj interface{}) error {
+	return json.Unmarshal(data, obj)
+}
+
+// jsonCodec is used by API groups that don't support protobuf, such as
+// ThirdPartyResources and CustomResourceDefinitions.
+var jsonCodec = jsonCodecType{}
+
+type pbCodecType struct{}
+
+func (pbCodecType) ContentType() string { return "application/vnd.kubernetes.protobuf" }
+
+func (pbCodecType) Marshal(obj interface{}) ([]byte, error) {
+	m, ok := obj.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("k8s: %T does not implement proto.Message", obj)
+	}
+	return proto.Marshal(m)
+}
+
+func (pbCodecType) Unmarshal(data []byte, obj interface{}) error {
+	m, ok := obj.(proto.Message)
+	if !ok {
+		return fmt.Errorf("k8s: %T does not implement proto.Message", obj)
+	}
+	return proto.Unmarshal(data, m)
+}
+
+// pbCodec is used by the built-in API groups, which all support protobuf.
+var pbCodec = pbCodecType{}
+
+// Client is a client for the Kubernetes API.
+type Client struct {
+	endpoint   string
+	namespace  string
+	httpClient *http.Client
+
+	// ValidateRequests, when true, runs Create/Update payloads through
+	// Validator before sending them to the apiserver, so malformed
+	// objects are rejected locally instead of round-tripping for a 422.
+	ValidateRequests bool
+
+	// Validator holds the OpenAPI-derived schemas ValidateRequests checks
+	// payloads against. It's nil until populated from a document fetched
+	// with Discovery.OpenAPISchema.
+	Validator *Validator
+
+	// Scheme maps GroupVersionKinds to the Go types used to decode them.
+	// Client.Watch and DynamicFor use it to pick a concrete type for each
+	// object they see on the wire, falling back to a generic map when a
+	// kind hasn't been registered.
+	Scheme *Scheme
+}
+
+// NewClient returns a Client talking to endpoint over httpClient, with an
+// empty Scheme ready for callers to Register types into.
+func NewClient(endpoint string, httpClient *http.Client) *Client {
+	return &Client{
+		endpoint:   endpoint,
+		httpClient: httpClient,
+		Scheme:     NewScheme(),
+	}
+}
+
+func (c *Client) urlForPath(p string) string {
+	return c.endpoint + "/" + p
+}
+
+func (c *Client) urlFor(apiGroup, apiVersion, namespace, resource, name string) string {
+	basePath := "api"
+	if apiGroup != "" {
+		basePath = path.Join("apis", apiGroup)
+	}
+
+	parts := []string{basePath, apiVersion}
+	if namespace != "" {
+		parts = append(parts, "namespaces", namespace)
+	}
+	parts = append(parts, resource)
+	if name != "" {
+		parts = append(parts, name)
+	}
+	return c.urlForPath(path.Join(parts...))
+}
+
+func (c *Client) do(ctx context.Context, method, url, accept, contentType string, body []byte) (*http.Response, error) {
+	var bodyReader *bytes.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	var req *http.Request
+	var err error
+	if bodyReader != nil {
+		req, err = http.NewRequest(method, url, bodyReader)
+	} else {
+		req, err = http.NewRequest(method, url, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("k8s: request to %s failed with status %d: %s", url, resp.StatusCode, data)
+	}
+	return resp, nil
+}
+
+// getBytes issues a GET against url and returns its raw response body,
+// requesting accept as the Accept header. An empty accept lets the server
+// pick its default representation; it's used by callers such as
+// Projection evaluation and OpenAPISchema that decode the body themselves
+// instead of going through a codec.
+func (c *Client) getBytes(ctx context.Context, url, accept string) ([]byte, error) {
+	r, err := c.do(ctx, "GET", url, accept, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+	return ioutil.ReadAll(r.Body)
+}
+
+func (c *Client) get(ctx context.Context, cdc codec, url string, resp interface{}) error {
+	r, err := c.do(ctx, "GET", url, cdc.ContentType(), "", nil)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	return cdc.Unmarshal(data, resp)
+}
+
+func (c *Client) create(ctx context.Context, cdc codec, method, url string, req, resp interface{}) error {
+	body, err := cdc.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	r, err := c.do(ctx, method, url, cdc.ContentType(), cdc.ContentType(), body)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	return cdc.Unmarshal(data, resp)
+}
+
+func (c *Client) delete(ctx context.Context, cdc codec, url string) error {
+	r, err := c.do(ctx, "DELETE", url, cdc.ContentType(), "", nil)
+	if err != nil {
+		return err
+	}
+	return r.Body.Close()
+}
+
+// unknown mirrors the runtime.Unknown object embedded in watch events,
+// carrying the event's not-yet-decoded payload.
+type unknown struct {
+	Raw []byte
+}
+
+// watcher streams decoded watch events off a single long-lived HTTP
+// response body.
+type watcher struct {
+	body io.ReadCloser
+	dec  *json.Decoder
+}
+
+func (c *Client) watch(ctx context.Context, url string) (*watcher, error) {
+	// Every watcher frames the stream as newline-delimited JSON and decodes
+	// unknown.Raw with jsonCodec, so the request has to negotiate JSON here
+	// too; the protobuf content type would (at best) hand back
+	// length-delimited protobuf frames json.Decoder can't parse, and
+	// JSON-only groups like ThirdPartyResources/CustomResources would 406.
+	r, err := c.do(ctx, "GET", url+"?watch=true", jsonCodec.ContentType(), "", nil)
+	if err != nil {
+		return nil, err
+	}
+	return &watcher{body: r.Body, dec: json.NewDecoder(r.Body)}, nil
+}
+
+func (w *watcher) next() (*versioned.Event, *unknown, error) {
+	var raw json.RawMessage
+	if err := w.dec.Decode(&raw); err != nil {
+		return nil, nil, err
+	}
+
+	var event versioned.Event
+	if err := json.Unmarshal(raw, &event); err != nil {
+		return nil, nil, err
+	}
+
+	var envelope struct {
+		Object json.RawMessage `json:"object"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, nil, err
+	}
+
+	return &event, &unknown{Raw: envelope.Object}, nil
+}
+
+func (w *watcher) Close() error {
+	return w.body.Close()
+}
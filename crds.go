@@ -0,0 +1,181 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"path"
+	"time"
+
+	"github.com/ericchiang/k8s/api/v1"
+	"github.com/ericchiang/k8s/apis/apiextensions/v1beta1"
+	"github.com/ericchiang/k8s/watch/versioned"
+)
+
+const (
+	crdAPIGroup   = "apiextensions.k8s.io"
+	crdAPIVersion = "v1beta1"
+)
+
+// CustomResources is a client used for interacting with CustomResourceDefinition
+// backed API groups. Like ThirdPartyResources, it uses JSON encoding since CRDs
+// do not expose a protobuf representation.
+//
+// CustomResourceDefinitions replaced ThirdPartyResources as the supported way
+// to extend the Kubernetes API. Prefer this client for new code; use
+// CreateCustomResourceDefinition to register the backing definition, then
+// interact with instances through CustomResources the same way one would use
+// ThirdPartyResources.
+type CustomResources struct {
+	c *Client
+
+	apiGroup   string
+	apiVersion string
+}
+
+// CustomResources returns a client for interacting with a CustomResourceDefinition
+// backed API group.
+func (c *Client) CustomResources(apiGroup, apiVersion string) *CustomResources {
+	return &CustomResources{c, apiGroup, apiVersion}
+}
+
+func (t *CustomResources) Create(ctx context.Context, resource, namespace string, req, resp interface{}) error {
+	if err := checkResource(t.apiGroup, t.apiVersion, resource, namespace, "not required"); err != nil {
+		return err
+	}
+	if err := validateRequest(t.c, t.apiGroup, t.apiVersion, req); err != nil {
+		return err
+	}
+	url := t.c.urlFor(t.apiGroup, t.apiVersion, namespace, resource, "")
+	return t.c.create(ctx, jsonCodec, "POST", url, req, resp)
+}
+
+func (t *CustomResources) Update(ctx context.Context, resource, namespace, name string, req, resp interface{}) error {
+	if err := checkResource(t.apiGroup, t.apiVersion, resource, namespace, name); err != nil {
+		return err
+	}
+	if err := validateRequest(t.c, t.apiGroup, t.apiVersion, req); err != nil {
+		return err
+	}
+	url := t.c.urlFor(t.apiGroup, t.apiVersion, namespace, resource, name)
+	return t.c.create(ctx, jsonCodec, "PUT", url, req, resp)
+}
+
+func (t *CustomResources) Get(ctx context.Context, resource, namespace, name string, resp interface{}) error {
+	if err := checkResource(t.apiGroup, t.apiVersion, resource, namespace, name); err != nil {
+		return err
+	}
+	url := t.c.urlFor(t.apiGroup, t.apiVersion, namespace, resource, name)
+	return t.c.get(ctx, jsonCodec, url, resp)
+}
+
+func (t *CustomResources) Delete(ctx context.Context, resource, namespace, name string) error {
+	if err := checkResource(t.apiGroup, t.apiVersion, resource, namespace, name); err != nil {
+		return err
+	}
+	url := t.c.urlFor(t.apiGroup, t.apiVersion, namespace, resource, name)
+	return t.c.delete(ctx, jsonCodec, url)
+}
+
+func (t *CustomResources) List(ctx context.Context, resource, namespace string, resp interface{}) error {
+	if err := checkResource(t.apiGroup, t.apiVersion, resource, namespace, "name not required"); err != nil {
+		return err
+	}
+	url := t.c.urlFor(t.apiGroup, t.apiVersion, namespace, resource, "")
+	return t.c.get(ctx, jsonCodec, url, resp)
+}
+
+type CustomResourceWatcher struct {
+	watcher *watcher
+}
+
+func (w *CustomResourceWatcher) Next() (*versioned.Event, interface{}, error) {
+	event, unknown, err := w.watcher.next()
+	if err != nil {
+		return nil, nil, err
+	}
+	var resp interface{}
+	if err := jsonCodec.Unmarshal(unknown.Raw, &resp); err != nil {
+		w.watcher.Close()
+		return nil, nil, err
+	}
+	return event, resp, nil
+}
+
+func (w *CustomResourceWatcher) Close() error {
+	return w.watcher.Close()
+}
+
+func (t *CustomResources) Watch(ctx context.Context, resource, namespace string) (*CustomResourceWatcher, error) {
+	if err := checkResource(t.apiGroup, t.apiVersion, resource, namespace, "name not required"); err != nil {
+		return nil, err
+	}
+	url := t.c.urlFor(t.apiGroup, t.apiVersion, namespace, resource, "")
+	watcher, err := t.c.watch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return &CustomResourceWatcher{watcher}, nil
+}
+
+// CreateCustomResourceDefinition registers a new CustomResourceDefinition with
+// the apiserver and blocks until its status reports both the Established and
+// NamesAccepted conditions as true, or until ctx is canceled.
+func (c *Client) CreateCustomResourceDefinition(ctx context.Context, group, version, kind, plural, singular string, scope v1beta1.ResourceScope) (*v1beta1.CustomResourceDefinition, error) {
+	if group == "" {
+		return nil, errors.New("no api group provided")
+	}
+	if plural == "" {
+		return nil, errors.New("no plural resource name provided")
+	}
+
+	name := plural + "." + group
+	crd := &v1beta1.CustomResourceDefinition{
+		Metadata: &v1.ObjectMeta{
+			Name: String(name),
+		},
+		Spec: &v1beta1.CustomResourceDefinitionSpec{
+			Group:   String(group),
+			Version: String(version),
+			Scope:   &scope,
+			Names: &v1beta1.CustomResourceDefinitionNames{
+				Plural:   String(plural),
+				Singular: String(singular),
+				Kind:     String(kind),
+			},
+		},
+	}
+
+	createURL := c.urlForPath(path.Join("apis", crdAPIGroup, crdAPIVersion, "customresourcedefinitions"))
+	if err := c.create(ctx, jsonCodec, "POST", createURL, crd, crd); err != nil {
+		return nil, err
+	}
+
+	getURL := c.urlForPath(path.Join("apis", crdAPIGroup, crdAPIVersion, "customresourcedefinitions", name))
+	for {
+		var cur v1beta1.CustomResourceDefinition
+		if err := c.get(ctx, jsonCodec, getURL, &cur); err != nil {
+			return nil, err
+		}
+		if crdConditionTrue(&cur, v1beta1.Established) && crdConditionTrue(&cur, v1beta1.NamesAccepted) {
+			return &cur, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+func crdConditionTrue(crd *v1beta1.CustomResourceDefinition, typ v1beta1.CustomResourceDefinitionConditionType) bool {
+	if crd.Status == nil {
+		return false
+	}
+	for _, cond := range crd.Status.Conditions {
+		if cond.Type != nil && *cond.Type == typ && cond.Status != nil && *cond.Status == v1beta1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
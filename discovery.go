@@ -2,14 +2,21 @@ package k8s
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"path"
-
-	"github.com/golang/protobuf/proto"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/ericchiang/k8s/api/unversioned"
 	"github.com/ericchiang/k8s/watch/versioned"
 )
 
+// maxConcurrentDiscovery bounds the number of in-flight requests
+// ServerResources issues while fanning out across API groups and versions.
+const maxConcurrentDiscovery = 10
+
 type Version struct {
 	Major        string `json:"major"`
 	Minor        string `json:"minor"`
@@ -65,17 +72,127 @@ func (d *Discovery) APIResources(ctx context.Context, groupName, groupVersion st
 
 }
 
+// ErrGroupDiscoveryFailed is returned by ServerResources when one or more
+// group/versions could not be queried. The resources that were successfully
+// discovered are still returned alongside this error so callers can make
+// progress with partial results.
+type ErrGroupDiscoveryFailed struct {
+	// Errors maps each failed "group/version" to the error encountered
+	// discovering it.
+	Errors map[string]error
+}
+
+func (e *ErrGroupDiscoveryFailed) Error() string {
+	var groupVersions []string
+	for gv := range e.Errors {
+		groupVersions = append(groupVersions, gv)
+	}
+	sort.Strings(groupVersions)
+	return fmt.Sprintf("discovery of %s failed", strings.Join(groupVersions, ", "))
+}
+
+func (d *Discovery) legacyAPIResources(ctx context.Context) (*unversioned.APIResourceList, error) {
+	var list unversioned.APIResourceList
+	if err := d.client.get(ctx, pbCodec, d.client.urlForPath(path.Join("api", "v1")), &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// ServerResources returns every API resource the server exposes, keyed by
+// "group/version" (the legacy core group is keyed as "/v1"). Requests for
+// each group/version are fanned out in parallel, bounded by
+// maxConcurrentDiscovery. If any group/version fails, ServerResources still
+// returns the resources it could discover, alongside an
+// *ErrGroupDiscoveryFailed describing what was missed.
+func (d *Discovery) ServerResources(ctx context.Context) (map[string]*unversioned.APIResourceList, error) {
+	type groupVersion struct {
+		key, groupName, version string
+	}
+
+	jobs := []groupVersion{{"/v1", "", "v1"}}
+
+	groups, err := d.APIGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups.Groups {
+		for _, v := range group.Versions {
+			jobs = append(jobs, groupVersion{v.GetGroupVersion(), group.GetName(), v.GetVersion()})
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		results = make(map[string]*unversioned.APIResourceList, len(jobs))
+		errs    = make(map[string]error)
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, maxConcurrentDiscovery)
+	)
+
+	for _, j := range jobs {
+		wg.Add(1)
+		go func(j groupVersion) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var (
+				list *unversioned.APIResourceList
+				err  error
+			)
+			if j.groupName == "" {
+				list, err = d.legacyAPIResources(ctx)
+			} else {
+				list, err = d.APIResources(ctx, j.groupName, j.version)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[j.key] = err
+				return
+			}
+			results[j.key] = list
+		}(j)
+	}
+	wg.Wait()
+
+	if len(errs) != 0 {
+		return results, &ErrGroupDiscoveryFailed{Errors: errs}
+	}
+	return results, nil
+}
+
+// APIWatcher watches a group/version's resource list for changes, decoding
+// each event into the Go type registered in a Scheme for its
+// GroupVersionKind, or into *unversioned.APIResource if nothing is
+// registered.
 type APIWatcher struct {
 	watcher *watcher
+	scheme  *Scheme
 }
 
-func (w *APIWatcher) Next() (*versioned.Event, *unversioned.APIResource, error) {
+func (w *APIWatcher) Next() (*versioned.Event, interface{}, error) {
 	event, unknown, err := w.watcher.next()
 	if err != nil {
 		return nil, nil, err
 	}
-	resp := new(unversioned.APIResource)
-	if err := proto.Unmarshal(unknown.Raw, resp); err != nil {
+
+	var tm typeMeta
+	if err := json.Unmarshal(unknown.Raw, &tm); err != nil {
+		w.watcher.Close()
+		return nil, nil, err
+	}
+	group, version := tm.groupVersion()
+
+	resp := w.scheme.new(group, version, tm.Kind)
+	if resp == nil {
+		resp = new(unversioned.APIResource)
+	}
+	if err := jsonCodec.Unmarshal(unknown.Raw, resp); err != nil {
+		w.watcher.Close()
 		return nil, nil, err
 	}
 	return event, resp, nil
@@ -91,5 +208,5 @@ func (d *Discovery) APIWatch(ctx context.Context, groupName, groupVersion string
 	if err != nil {
 		return nil, err
 	}
-	return &APIWatcher{watcher}, nil
+	return &APIWatcher{watcher, d.client.Scheme}, nil
 }
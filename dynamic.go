@@ -0,0 +1,195 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/ericchiang/k8s/watch/versioned"
+)
+
+// typeMeta is the minimal shape every Kubernetes API object exposes, used to
+// sniff out an event or response's GroupVersionKind before handing it off
+// to a Scheme.
+type typeMeta struct {
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+}
+
+func (t typeMeta) groupVersion() (group, version string) {
+	if i := strings.LastIndex(t.APIVersion, "/"); i != -1 {
+		return t.APIVersion[:i], t.APIVersion[i+1:]
+	}
+	return "", t.APIVersion
+}
+
+// GenericWatcher watches a GroupVersionResource, decoding each event into
+// the Go type registered in a Scheme for its GroupVersionKind, or into a
+// generic map[string]interface{} if nothing is registered.
+type GenericWatcher struct {
+	watcher *watcher
+	scheme  *Scheme
+}
+
+func (w *GenericWatcher) Next() (*versioned.Event, interface{}, error) {
+	event, unknown, err := w.watcher.next()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tm typeMeta
+	if err := json.Unmarshal(unknown.Raw, &tm); err != nil {
+		w.watcher.Close()
+		return nil, nil, err
+	}
+	group, version := tm.groupVersion()
+
+	obj := w.scheme.new(group, version, tm.Kind)
+	if obj == nil {
+		obj = new(map[string]interface{})
+	}
+	if err := jsonCodec.Unmarshal(unknown.Raw, obj); err != nil {
+		w.watcher.Close()
+		return nil, nil, err
+	}
+	return event, obj, nil
+}
+
+func (w *GenericWatcher) Close() error {
+	return w.watcher.Close()
+}
+
+// Watch streams changes to every object in gvr's collection, decoding each
+// into the Go type c.Scheme has registered for its kind.
+func (c *Client) Watch(ctx context.Context, gvr GroupVersionResource, namespace string) (*GenericWatcher, error) {
+	url := c.urlFor(gvr.Group, gvr.Version, namespace, gvr.Resource, "")
+	watcher, err := c.watch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return &GenericWatcher{watcher, c.Scheme}, nil
+}
+
+// DynamicResource is a client for a single GroupVersionResource that
+// doesn't require a hand-written typed client: responses are decoded using
+// whatever Go type c.Scheme has registered for the object's kind, falling
+// back to a generic map when nothing is registered.
+type DynamicResource struct {
+	c   *Client
+	gvr GroupVersionResource
+}
+
+// DynamicFor returns a client for gvr backed by c.Scheme.
+func (c *Client) DynamicFor(gvr GroupVersionResource) *DynamicResource {
+	return &DynamicResource{c, gvr}
+}
+
+func (d *DynamicResource) objFor(kind string) interface{} {
+	if obj := d.c.Scheme.new(d.gvr.Group, d.gvr.Version, kind); obj != nil {
+		return obj
+	}
+	return new(map[string]interface{})
+}
+
+func (d *DynamicResource) decode(data []byte) (interface{}, error) {
+	var tm typeMeta
+	if err := json.Unmarshal(data, &tm); err != nil {
+		return nil, err
+	}
+	obj := d.objFor(tm.Kind)
+	if err := json.Unmarshal(data, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (d *DynamicResource) Get(ctx context.Context, namespace, name string) (interface{}, error) {
+	if err := checkResource(d.gvr.Group, d.gvr.Version, d.gvr.Resource, namespace, name); err != nil {
+		return nil, err
+	}
+	url := d.c.urlFor(d.gvr.Group, d.gvr.Version, namespace, d.gvr.Resource, name)
+	data, err := d.c.getBytes(ctx, url, "")
+	if err != nil {
+		return nil, err
+	}
+	return d.decode(data)
+}
+
+// List returns the collection's contents, decoded into the list type
+// c.Scheme registered alongside kind's single-object type.
+func (d *DynamicResource) List(ctx context.Context, namespace string) (interface{}, error) {
+	if err := checkResource(d.gvr.Group, d.gvr.Version, d.gvr.Resource, namespace, "name not required"); err != nil {
+		return nil, err
+	}
+	url := d.c.urlFor(d.gvr.Group, d.gvr.Version, namespace, d.gvr.Resource, "")
+	data, err := d.c.getBytes(ctx, url, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var tm typeMeta
+	if err := json.Unmarshal(data, &tm); err != nil {
+		return nil, err
+	}
+	kind := strings.TrimSuffix(tm.Kind, "List")
+	obj := d.c.Scheme.newList(d.gvr.Group, d.gvr.Version, kind)
+	if obj == nil {
+		obj = new(map[string]interface{})
+	}
+	if err := json.Unmarshal(data, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (d *DynamicResource) Create(ctx context.Context, namespace string, req interface{}) (interface{}, error) {
+	if err := checkResource(d.gvr.Group, d.gvr.Version, d.gvr.Resource, namespace, "not required"); err != nil {
+		return nil, err
+	}
+	if err := validateRequest(d.c, d.gvr.Group, d.gvr.Version, req); err != nil {
+		return nil, err
+	}
+	url := d.c.urlFor(d.gvr.Group, d.gvr.Version, namespace, d.gvr.Resource, "")
+	resp := d.objFor(kindOf(req))
+	if err := d.c.create(ctx, jsonCodec, "POST", url, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (d *DynamicResource) Update(ctx context.Context, namespace, name string, req interface{}) (interface{}, error) {
+	if err := checkResource(d.gvr.Group, d.gvr.Version, d.gvr.Resource, namespace, name); err != nil {
+		return nil, err
+	}
+	if err := validateRequest(d.c, d.gvr.Group, d.gvr.Version, req); err != nil {
+		return nil, err
+	}
+	url := d.c.urlFor(d.gvr.Group, d.gvr.Version, namespace, d.gvr.Resource, name)
+	resp := d.objFor(kindOf(req))
+	if err := d.c.create(ctx, jsonCodec, "PUT", url, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (d *DynamicResource) Delete(ctx context.Context, namespace, name string) error {
+	if err := checkResource(d.gvr.Group, d.gvr.Version, d.gvr.Resource, namespace, name); err != nil {
+		return err
+	}
+	url := d.c.urlFor(d.gvr.Group, d.gvr.Version, namespace, d.gvr.Resource, name)
+	return d.c.delete(ctx, jsonCodec, url)
+}
+
+// Watch streams changes to the collection; it's a convenience wrapper
+// around Client.Watch for d's GroupVersionResource.
+func (d *DynamicResource) Watch(ctx context.Context, namespace string) (*GenericWatcher, error) {
+	return d.c.Watch(ctx, d.gvr, namespace)
+}
+
+func kindOf(obj interface{}) string {
+	tm, ok := obj.(typeMetaKind)
+	if !ok {
+		return ""
+	}
+	return tm.GetKind()
+}
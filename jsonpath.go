@@ -0,0 +1,339 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Projection is a compiled JSONPath expression used to pull matching
+// sub-values out of a decoded JSON document without defining a full Go
+// type for it.
+//
+// Supported syntax: $, .field, ['field'], [*], [n], [start:end], recursive
+// descent .., and simple equality filters [?(@.field=="value")].
+type Projection struct {
+	steps []pathStep
+}
+
+// CompileProjection parses a JSONPath expression into a Projection.
+func CompileProjection(expr string) (*Projection, error) {
+	steps, err := (&jsonPathParser{input: expr}).parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Projection{steps: steps}, nil
+}
+
+// Evaluate runs the projection against a decoded JSON document (as produced
+// by json.Unmarshal into interface{}) and returns every matching
+// sub-value. A nil doc is treated as an empty array rather than a type
+// error.
+func (p *Projection) Evaluate(doc interface{}) ([]interface{}, error) {
+	values := []interface{}{doc}
+	for _, step := range p.steps {
+		var next []interface{}
+		for _, v := range values {
+			next = append(next, step.apply(v)...)
+		}
+		values = next
+	}
+	return values, nil
+}
+
+type pathStep interface {
+	apply(value interface{}) []interface{}
+}
+
+type fieldStep struct{ name string }
+
+func (s fieldStep) apply(value interface{}) []interface{} {
+	m, ok := asObject(value)
+	if !ok {
+		return nil
+	}
+	if v, ok := m[s.name]; ok {
+		return []interface{}{v}
+	}
+	return nil
+}
+
+type indexStep struct{ n int }
+
+func (s indexStep) apply(value interface{}) []interface{} {
+	a, ok := asArray(value)
+	if !ok {
+		return nil
+	}
+	i := s.n
+	if i < 0 {
+		i += len(a)
+	}
+	if i < 0 || i >= len(a) {
+		return nil
+	}
+	return []interface{}{a[i]}
+}
+
+type sliceStep struct {
+	start, end       int
+	hasStart, hasEnd bool
+}
+
+func (s sliceStep) apply(value interface{}) []interface{} {
+	a, ok := asArray(value)
+	if !ok {
+		return nil
+	}
+	start, end := 0, len(a)
+	if s.hasStart {
+		start = s.start
+		if start < 0 {
+			start += len(a)
+		}
+	}
+	if s.hasEnd {
+		end = s.end
+		if end < 0 {
+			end += len(a)
+		}
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end > len(a) {
+		end = len(a)
+	}
+	if start >= end {
+		return nil
+	}
+	return append([]interface{}{}, a[start:end]...)
+}
+
+type wildcardStep struct{}
+
+func (s wildcardStep) apply(value interface{}) []interface{} {
+	if a, ok := asArray(value); ok {
+		return append([]interface{}{}, a...)
+	}
+	if m, ok := asObject(value); ok {
+		values := make([]interface{}, 0, len(m))
+		for _, v := range m {
+			values = append(values, v)
+		}
+		return values
+	}
+	return nil
+}
+
+// recursiveStep applies next at every level of value's tree, not just the
+// immediate children, implementing JSONPath's ".." descent.
+type recursiveStep struct{ next pathStep }
+
+func (s recursiveStep) apply(value interface{}) []interface{} {
+	var matches []interface{}
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		matches = append(matches, s.next.apply(v)...)
+		if a, ok := asArray(v); ok {
+			for _, e := range a {
+				walk(e)
+			}
+		}
+		if m, ok := asObject(v); ok {
+			for _, e := range m {
+				walk(e)
+			}
+		}
+	}
+	walk(value)
+	return matches
+}
+
+// filterStep keeps the elements of an array whose field equals value,
+// implementing [?(@.field==value)].
+type filterStep struct {
+	field string
+	value string
+}
+
+func (s filterStep) apply(value interface{}) []interface{} {
+	a, ok := asArray(value)
+	if !ok {
+		return nil
+	}
+	var matches []interface{}
+	for _, e := range a {
+		m, ok := asObject(e)
+		if !ok {
+			continue
+		}
+		if v, ok := m[s.field]; ok && fmt.Sprint(v) == s.value {
+			matches = append(matches, e)
+		}
+	}
+	return matches
+}
+
+func asObject(value interface{}) (map[string]interface{}, bool) {
+	m, ok := value.(map[string]interface{})
+	return m, ok
+}
+
+// asArray treats a nil value as an empty array rather than a type
+// mismatch, since missing fields decode to nil.
+func asArray(value interface{}) ([]interface{}, bool) {
+	if value == nil {
+		return nil, true
+	}
+	a, ok := value.([]interface{})
+	return a, ok
+}
+
+type jsonPathParser struct {
+	input string
+	pos   int
+}
+
+func (p *jsonPathParser) parse() ([]pathStep, error) {
+	if !strings.HasPrefix(p.input, "$") {
+		return nil, fmt.Errorf("jsonpath: expression must start with $: %q", p.input)
+	}
+	p.pos = 1
+
+	var steps []pathStep
+	for p.pos < len(p.input) {
+		switch {
+		case strings.HasPrefix(p.input[p.pos:], ".."):
+			p.pos += 2
+			name, err := p.readIdentifier()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, recursiveStep{fieldStep{name}})
+		case p.input[p.pos] == '.':
+			p.pos++
+			name, err := p.readIdentifier()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, fieldStep{name})
+		case p.input[p.pos] == '[':
+			step, err := p.readBracket()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+		default:
+			return nil, fmt.Errorf("jsonpath: unexpected character %q at %d", p.input[p.pos], p.pos)
+		}
+	}
+	return steps, nil
+}
+
+func (p *jsonPathParser) readIdentifier() (string, error) {
+	start := p.pos
+	for p.pos < len(p.input) && isIdentChar(p.input[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", fmt.Errorf("jsonpath: expected field name at %d", start)
+	}
+	return p.input[start:p.pos], nil
+}
+
+func isIdentChar(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+func (p *jsonPathParser) readBracket() (pathStep, error) {
+	end := strings.IndexByte(p.input[p.pos:], ']')
+	if end == -1 {
+		return nil, fmt.Errorf("jsonpath: unterminated [ at %d", p.pos)
+	}
+	inner := p.input[p.pos+1 : p.pos+end]
+	p.pos += end + 1
+
+	switch {
+	case inner == "*":
+		return wildcardStep{}, nil
+	case strings.HasPrefix(inner, "'") && strings.HasSuffix(inner, "'"):
+		return fieldStep{strings.Trim(inner, "'")}, nil
+	case strings.HasPrefix(inner, `"`) && strings.HasSuffix(inner, `"`):
+		return fieldStep{strings.Trim(inner, `"`)}, nil
+	case strings.HasPrefix(inner, "?("):
+		return parseFilter(inner)
+	case strings.Contains(inner, ":"):
+		return parseSlice(inner)
+	default:
+		n, err := strconv.Atoi(inner)
+		if err != nil {
+			return nil, fmt.Errorf("jsonpath: invalid index %q", inner)
+		}
+		return indexStep{n}, nil
+	}
+}
+
+func parseSlice(inner string) (pathStep, error) {
+	parts := strings.SplitN(inner, ":", 2)
+	step := sliceStep{}
+	if parts[0] != "" {
+		n, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("jsonpath: invalid slice start %q", parts[0])
+		}
+		step.start, step.hasStart = n, true
+	}
+	if len(parts) > 1 && parts[1] != "" {
+		n, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("jsonpath: invalid slice end %q", parts[1])
+		}
+		step.end, step.hasEnd = n, true
+	}
+	return step, nil
+}
+
+func parseFilter(inner string) (pathStep, error) {
+	body := strings.TrimSuffix(strings.TrimPrefix(inner, "?("), ")")
+	eq := strings.Index(body, "==")
+	if eq == -1 {
+		return nil, fmt.Errorf("jsonpath: unsupported filter %q", inner)
+	}
+	field := strings.TrimPrefix(strings.TrimSpace(body[:eq]), "@.")
+	value := strings.Trim(strings.TrimSpace(body[eq+2:]), `"'`)
+	return filterStep{field: field, value: value}, nil
+}
+
+// projectURL fetches the JSON response at url and, if projection is
+// non-nil, evaluates it against the decoded document, returning only the
+// matching sub-values. With a nil projection the whole decoded document is
+// returned as the sole element.
+func (c *Client) projectURL(ctx context.Context, url string, projection *Projection) ([]interface{}, error) {
+	data, err := c.getBytes(ctx, url, "")
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if len(data) != 0 {
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+	}
+	if projection == nil {
+		return []interface{}{doc}, nil
+	}
+	return projection.Evaluate(doc)
+}
+
+// GetRaw fetches the JSON response at apiPath (the same form accepted by
+// urlForPath internally, e.g. "apis/batch/v1" or "api/v1") and, if
+// projection is non-nil, evaluates it and returns only the matching
+// sub-values. This is the cheap, server-side-ish field extraction used by
+// controllers that only need a handful of fields out of a large response,
+// without defining full Go types for it.
+func (c *Client) GetRaw(ctx context.Context, apiPath string, projection *Projection) ([]interface{}, error) {
+	return c.projectURL(ctx, c.urlForPath(apiPath), projection)
+}
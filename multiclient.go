@@ -0,0 +1,206 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ericchiang/k8s/api/unversioned"
+)
+
+// clusterAnnotation is stamped onto every item MultiCustomResources.List
+// concatenates, naming the cluster it was read from.
+const clusterAnnotation = "k8s.io/source-cluster"
+
+// MultiClient federates a set of *Client instances, typically one per
+// context of a single kubeconfig, so callers can fan discovery and CRD
+// operations out across many clusters without hand-rolling goroutines.
+// It does not read kubeconfig files itself: NewMultiClient takes clients
+// that have already been constructed.
+type MultiClient struct {
+	clients map[string]*Client
+
+	// MaxConcurrency bounds how many clusters are contacted at once by
+	// ForEach and the federated helpers built on it. Zero means
+	// unbounded.
+	MaxConcurrency int
+}
+
+// NewMultiClient federates clients, keyed by cluster name (typically a
+// kubeconfig context name).
+func NewMultiClient(clients map[string]*Client) *MultiClient {
+	return &MultiClient{clients: clients}
+}
+
+// MultiError aggregates the per-cluster errors a federated operation
+// encountered. Clusters not present in Errors succeeded.
+type MultiError struct {
+	Errors map[string]error
+}
+
+func (e *MultiError) Error() string {
+	var names []string
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("operation failed for clusters: %s", strings.Join(names, ", "))
+}
+
+// ForEach runs fn against every federated client, fanning out with
+// concurrency bounded by m.MaxConcurrency (unbounded if zero). It returns a
+// *MultiError aggregating every cluster whose fn call returned an error.
+func (m *MultiClient) ForEach(ctx context.Context, fn func(name string, c *Client) error) error {
+	var (
+		mu   sync.Mutex
+		errs = map[string]error{}
+		wg   sync.WaitGroup
+		sem  chan struct{}
+	)
+	if m.MaxConcurrency > 0 {
+		sem = make(chan struct{}, m.MaxConcurrency)
+	}
+
+	for name, c := range m.clients {
+		wg.Add(1)
+		go func(name string, c *Client) {
+			defer wg.Done()
+			if sem != nil {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+			}
+
+			if err := fn(name, c); err != nil {
+				mu.Lock()
+				errs[name] = err
+				mu.Unlock()
+			}
+		}(name, c)
+	}
+	wg.Wait()
+
+	if len(errs) != 0 {
+		return &MultiError{Errors: errs}
+	}
+	return nil
+}
+
+// MultiDiscovery federates Discovery across every client in a MultiClient.
+type MultiDiscovery struct {
+	m *MultiClient
+}
+
+// Discovery returns a federated discovery client.
+func (m *MultiClient) Discovery() *MultiDiscovery {
+	return &MultiDiscovery{m}
+}
+
+// ServerResources fans Discovery.ServerResources out across every
+// federated client, keyed first by cluster name and then by
+// "group/version". A cluster whose discovery failed, partially or fully,
+// is still represented in the returned error via MultiError, but any
+// resources it did manage to discover are still present in the result.
+func (d *MultiDiscovery) ServerResources(ctx context.Context) (map[string]map[string]*unversioned.APIResourceList, error) {
+	results := make(map[string]map[string]*unversioned.APIResourceList, len(d.m.clients))
+	var mu sync.Mutex
+
+	err := d.m.ForEach(ctx, func(name string, c *Client) error {
+		resources, err := c.Discovery().ServerResources(ctx)
+
+		mu.Lock()
+		if resources != nil {
+			results[name] = resources
+		}
+		mu.Unlock()
+
+		return err
+	})
+	return results, err
+}
+
+// MultiCustomResources federates CustomResources across every client in a
+// MultiClient.
+type MultiCustomResources struct {
+	m   *MultiClient
+	gvr GroupVersionResource
+}
+
+// CustomResources returns a federated CustomResources client for gvr.
+func (m *MultiClient) CustomResources(gvr GroupVersionResource) *MultiCustomResources {
+	return &MultiCustomResources{m, gvr}
+}
+
+// List concatenates gvr's contents across every federated cluster into
+// protoList, stamping each item's source cluster into the
+// "k8s.io/source-cluster" annotation. protoList must be a pointer to a
+// struct with an Items slice, the same shape CustomResources.List expects.
+func (m *MultiCustomResources) List(ctx context.Context, namespace string, protoList interface{}) error {
+	listPtr := reflect.ValueOf(protoList)
+	if listPtr.Kind() != reflect.Ptr || listPtr.Elem().Kind() != reflect.Struct {
+		return errors.New("k8s: protoList must be a pointer to a struct")
+	}
+	itemsField := listPtr.Elem().FieldByName("Items")
+	if !itemsField.IsValid() || itemsField.Kind() != reflect.Slice {
+		return errors.New("k8s: protoList has no Items slice field")
+	}
+	listType := listPtr.Elem().Type()
+
+	var mu sync.Mutex
+	return m.m.ForEach(ctx, func(name string, c *Client) error {
+		perCluster := reflect.New(listType).Interface()
+		if err := c.CustomResources(m.gvr.Group, m.gvr.Version).List(ctx, m.gvr.Resource, namespace, perCluster); err != nil {
+			return err
+		}
+
+		perClusterItems := reflect.ValueOf(perCluster).Elem().FieldByName("Items")
+
+		mu.Lock()
+		defer mu.Unlock()
+		for i := 0; i < perClusterItems.Len(); i++ {
+			item := perClusterItems.Index(i)
+			stampSourceCluster(item, name)
+			itemsField.Set(reflect.Append(itemsField, item))
+		}
+		return nil
+	})
+}
+
+// objectOf recovers the object interface (GetMetadata() *v1.ObjectMeta,
+// implemented with a pointer receiver on the generated types) from an
+// Items element regardless of whether Items holds values or pointers: for
+// a value element it falls back to item's address, which is always
+// available here since it comes from a slice field reached through a
+// pointer's Elem().
+func objectOf(item reflect.Value) (object, bool) {
+	if obj, ok := item.Interface().(object); ok {
+		return obj, true
+	}
+	if item.CanAddr() {
+		if obj, ok := item.Addr().Interface().(object); ok {
+			return obj, true
+		}
+	}
+	return nil, false
+}
+
+// stampSourceCluster records which cluster item was read from, via the
+// object interface declared alongside ThirdPartyResources to reach an
+// object's metadata.
+func stampSourceCluster(item reflect.Value, cluster string) {
+	obj, ok := objectOf(item)
+	if !ok {
+		return
+	}
+	meta := obj.GetMetadata()
+	if meta == nil {
+		return
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[clusterAnnotation] = cluster
+}
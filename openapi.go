@@ -0,0 +1,217 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	openapi_v2 "github.com/googleapis/gnostic/OpenAPIv2"
+)
+
+const (
+	openAPIProtobufAccept = "application/com.github.proto-openapi.spec.v2@v1.0+protobuf"
+	openAPIJSONAccept     = "application/json"
+)
+
+// OpenAPISchema fetches the server's OpenAPI document describing every type
+// and operation it exposes. The protobuf encoding is requested first, since
+// it's both smaller and what the apiserver produces natively; servers that
+// only understand the legacy JSON form are retried automatically.
+func (d *Discovery) OpenAPISchema(ctx context.Context) (*openapi_v2.Document, error) {
+	url := d.client.urlForPath("openapi/v2")
+
+	if data, err := d.client.getBytes(ctx, url, openAPIProtobufAccept); err == nil {
+		doc := new(openapi_v2.Document)
+		if err := proto.Unmarshal(data, doc); err == nil {
+			return doc, nil
+		}
+	}
+
+	data, err := d.client.getBytes(ctx, url, openAPIJSONAccept)
+	if err != nil {
+		return nil, fmt.Errorf("fetching openapi schema: %v", err)
+	}
+	return openapi_v2.ParseDocument(data)
+}
+
+type groupVersionKind struct {
+	group, version, kind string
+}
+
+// Validator validates objects against the definitions of an OpenAPI schema
+// before they're sent to the apiserver, so malformed payloads can be
+// rejected locally instead of round-tripping for a 422.
+type Validator struct {
+	byGVK map[groupVersionKind]*openapi_v2.Schema
+}
+
+// NewValidator indexes an OpenAPI document's definitions by the
+// GroupVersionKind each carries in its "x-kubernetes-group-version-kind"
+// vendor extension, so Validate can look schemas up by GVK.
+func NewValidator(doc *openapi_v2.Document) *Validator {
+	v := &Validator{byGVK: map[groupVersionKind]*openapi_v2.Schema{}}
+	for _, def := range doc.GetDefinitions().GetAdditionalProperties() {
+		schema := def.GetValue()
+		for _, ext := range schema.GetVendorExtension() {
+			if ext.GetName() != "x-kubernetes-group-version-kind" {
+				continue
+			}
+			for _, gvk := range decodeGVKExtension(ext.GetValue()) {
+				v.byGVK[gvk] = schema
+			}
+		}
+	}
+	return v
+}
+
+func decodeGVKExtension(any *openapi_v2.Any) []groupVersionKind {
+	var entries []struct {
+		Group   string `json:"group"`
+		Version string `json:"version"`
+		Kind    string `json:"kind"`
+	}
+	if err := json.Unmarshal([]byte(any.GetYaml()), &entries); err != nil {
+		return nil
+	}
+	gvks := make([]groupVersionKind, 0, len(entries))
+	for _, e := range entries {
+		gvks = append(gvks, groupVersionKind{e.Group, e.Version, e.Kind})
+	}
+	return gvks
+}
+
+// ValidationErrors collects every constraint a validated object violated.
+type ValidationErrors []string
+
+func (e ValidationErrors) Error() string {
+	return fmt.Sprintf("object failed validation: %s", strings.Join(e, "; "))
+}
+
+// Validate checks obj against the schema registered for group/version/kind,
+// returning a ValidationErrors describing every required field, type, and
+// enum constraint it violates. It returns an error if no schema is
+// registered for that GroupVersionKind.
+func (v *Validator) Validate(group, version, kind string, obj interface{}) error {
+	schema, ok := v.byGVK[groupVersionKind{group, version, kind}]
+	if !ok {
+		return fmt.Errorf("k8s: no openapi schema registered for group=%q version=%q kind=%q", group, version, kind)
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	var errs ValidationErrors
+	validateAgainstSchema(schema, value, "$", &errs)
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateAgainstSchema(schema *openapi_v2.Schema, value interface{}, path string, errs *ValidationErrors) {
+	obj, isObject := value.(map[string]interface{})
+
+	for _, name := range schema.GetRequired() {
+		if !isObject {
+			continue
+		}
+		if _, ok := obj[name]; !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: missing required field %q", path, name))
+		}
+	}
+
+	if types := schema.GetType().GetValue(); len(types) != 0 && !anyTypeMatches(types, value) {
+		*errs = append(*errs, fmt.Sprintf("%s: value does not match type %v", path, types))
+	}
+
+	if enum := schema.GetEnum(); len(enum) != 0 && !enumContains(enum, value) {
+		*errs = append(*errs, fmt.Sprintf("%s: value not among allowed enum values", path))
+	}
+
+	if !isObject {
+		return
+	}
+	for _, prop := range schema.GetProperties().GetAdditionalProperties() {
+		child, ok := obj[prop.GetName()]
+		if !ok {
+			continue
+		}
+		validateAgainstSchema(prop.GetValue(), child, path+"."+prop.GetName(), errs)
+	}
+}
+
+func anyTypeMatches(types []string, value interface{}) bool {
+	for _, t := range types {
+		switch t {
+		case "object":
+			if _, ok := value.(map[string]interface{}); ok {
+				return true
+			}
+		case "array":
+			if _, ok := value.([]interface{}); ok {
+				return true
+			}
+		case "string":
+			if _, ok := value.(string); ok {
+				return true
+			}
+		case "boolean":
+			if _, ok := value.(bool); ok {
+				return true
+			}
+		case "integer", "number":
+			if _, ok := value.(float64); ok {
+				return true
+			}
+		case "null":
+			if value == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func enumContains(enum []*openapi_v2.Any, value interface{}) bool {
+	for _, e := range enum {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(e.GetYaml()), &decoded); err != nil {
+			continue
+		}
+		if reflect.DeepEqual(decoded, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// typeMetaKind is implemented by the generated TypeMeta types embedded in
+// API objects, giving validateRequest a GVK to look the object's schema up
+// by without requiring every caller to pass it explicitly.
+type typeMetaKind interface {
+	GetKind() string
+}
+
+// validateRequest runs obj through validator when the client has opted in
+// via ValidateRequests. It's a no-op whenever validation isn't enabled, no
+// validator has been set, or obj doesn't expose a Kind to look its schema
+// up by.
+func validateRequest(c *Client, group, version string, obj interface{}) error {
+	if !c.ValidateRequests || c.Validator == nil {
+		return nil
+	}
+	tm, ok := obj.(typeMetaKind)
+	if !ok {
+		return nil
+	}
+	return c.Validator.Validate(group, version, tm.GetKind(), obj)
+}
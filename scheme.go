@@ -0,0 +1,88 @@
+package k8s
+
+import (
+	"reflect"
+	"sync"
+)
+
+// GroupVersionResource identifies a collection of API objects within a
+// group and version, e.g. {Group: "metrics.example.com", Version: "v1",
+// Resource: "metrics"}.
+type GroupVersionResource struct {
+	Group    string
+	Version  string
+	Resource string
+}
+
+// schemeKey identifies a registered Go type by the GroupVersionKind of the
+// API object it decodes.
+type schemeKey struct {
+	apiGroup, apiVersion, kind string
+}
+
+// Scheme is a registry mapping a GroupVersionKind to the Go types used to
+// decode it. It lets generic code such as Client.Watch and DynamicResource
+// pick the right concrete type for an object it sees on the wire, without
+// the caller hand-writing a typed client for every kind.
+type Scheme struct {
+	mu    sync.RWMutex
+	types map[schemeKey]reflect.Type
+	lists map[schemeKey]reflect.Type
+}
+
+// NewScheme returns an empty Scheme.
+func NewScheme() *Scheme {
+	return &Scheme{
+		types: map[schemeKey]reflect.Type{},
+		lists: map[schemeKey]reflect.Type{},
+	}
+}
+
+// Register associates a GroupVersionKind with proto, the Go value used to
+// decode a single object of that kind, and list, the Go value used to
+// decode a list of them. Both are typically passed as a pointer to a zero
+// value:
+//
+//	scheme.Register("metrics.example.com", "v1", "Metric", new(Metric), new(MetricList))
+func (s *Scheme) Register(apiGroup, apiVersion, kind string, proto, list interface{}) {
+	key := schemeKey{apiGroup, apiVersion, kind}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.types[key] = elemType(proto)
+	if list != nil {
+		s.lists[key] = elemType(list)
+	}
+}
+
+func elemType(v interface{}) reflect.Type {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// new allocates a fresh pointer to the Go type registered for
+// apiGroup/apiVersion/kind, or nil if nothing is registered for it.
+func (s *Scheme) new(apiGroup, apiVersion, kind string) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.types[schemeKey{apiGroup, apiVersion, kind}]
+	if !ok {
+		return nil
+	}
+	return reflect.New(t).Interface()
+}
+
+// newList allocates a fresh pointer to the list type registered for
+// apiGroup/apiVersion/kind, or nil if nothing is registered for it.
+func (s *Scheme) newList(apiGroup, apiVersion, kind string) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.lists[schemeKey{apiGroup, apiVersion, kind}]
+	if !ok {
+		return nil
+	}
+	return reflect.New(t).Interface()
+}
@@ -3,7 +3,6 @@ package k8s
 import (
 	"context"
 	"errors"
-	"fmt"
 
 	"github.com/ericchiang/k8s/api/v1"
 	"github.com/ericchiang/k8s/watch/versioned"
@@ -120,6 +119,9 @@ func (t *ThirdPartyResources) Create(ctx context.Context, resource, namespace st
 	if err := checkResource(t.apiGroup, t.apiVersion, resource, namespace, "not required"); err != nil {
 		return err
 	}
+	if err := validateRequest(t.c, t.apiGroup, t.apiVersion, req); err != nil {
+		return err
+	}
 	url := t.c.urlFor(t.apiGroup, t.apiVersion, namespace, resource, "")
 	return t.c.create(ctx, jsonCodec, "POST", url, req, resp)
 }
@@ -128,6 +130,9 @@ func (t *ThirdPartyResources) Update(ctx context.Context, resource, namespace, n
 	if err := checkResource(t.apiGroup, t.apiVersion, resource, namespace, "not required"); err != nil {
 		return err
 	}
+	if err := validateRequest(t.c, t.apiGroup, t.apiVersion, req); err != nil {
+		return err
+	}
 	url := t.c.urlFor(t.apiGroup, t.apiVersion, namespace, resource, name)
 	return t.c.create(ctx, jsonCodec, "PUT", url, req, resp)
 }
@@ -156,20 +161,54 @@ func (t *ThirdPartyResources) List(ctx context.Context, resource, namespace stri
 	return t.c.get(ctx, jsonCodec, url, resp)
 }
 
+// GetProjected behaves like Get, but instead of decoding the full response
+// into a struct, it evaluates projection against the decoded JSON body and
+// returns only the matching sub-values.
+func (t *ThirdPartyResources) GetProjected(ctx context.Context, resource, namespace, name string, projection *Projection) ([]interface{}, error) {
+	if err := checkResource(t.apiGroup, t.apiVersion, resource, namespace, name); err != nil {
+		return nil, err
+	}
+	url := t.c.urlFor(t.apiGroup, t.apiVersion, namespace, resource, name)
+	return t.c.projectURL(ctx, url, projection)
+}
+
+// ListProjected behaves like List, but instead of decoding the full
+// response into a struct, it evaluates projection against the decoded
+// JSON body and returns only the matching sub-values. This is the cheap
+// way to pull a handful of fields, e.g. .items[*].status.conditions, out
+// of a large collection without defining a full Go type for it.
+func (t *ThirdPartyResources) ListProjected(ctx context.Context, resource, namespace string, projection *Projection) ([]interface{}, error) {
+	if err := checkResource(t.apiGroup, t.apiVersion, resource, namespace, "name not required"); err != nil {
+		return nil, err
+	}
+	url := t.c.urlFor(t.apiGroup, t.apiVersion, namespace, resource, "")
+	return t.c.projectURL(ctx, url, projection)
+}
+
+// ThirdPartyResourceWatcher watches a ThirdPartyResources collection,
+// decoding each event into a fresh instance produced by new.
 type ThirdPartyResourceWatcher struct {
 	watcher *watcher
+	new     func() interface{}
 }
 
+// Next blocks until the next watch event is available, JSON decoding its
+// object into a new instance of the type produced by the factory passed to
+// Watch. It returns an error if the underlying stream fails or the event
+// payload cannot be decoded; any failure closes the underlying connection
+// before returning, so callers only need to Close the watcher themselves
+// once they're done reading successfully decoded events.
 func (w *ThirdPartyResourceWatcher) Next() (*versioned.Event, interface{}, error) {
 	event, unknown, err := w.watcher.next()
 	if err != nil {
+		w.watcher.Close()
 		return nil, nil, err
 	}
-	fmt.Println(unknown.Raw)
-	var resp interface{}
-	/* if err := proto.Unmarshal(unknown.Raw, resp); err != nil {
+	resp := w.new()
+	if err := jsonCodec.Unmarshal(unknown.Raw, resp); err != nil {
+		w.watcher.Close()
 		return nil, nil, err
-	} */
+	}
 	return event, resp, nil
 }
 
@@ -177,7 +216,10 @@ func (w *ThirdPartyResourceWatcher) Close() error {
 	return w.watcher.Close()
 }
 
-func (t *ThirdPartyResources) Watch(ctx context.Context, resource, namespace string) (*ThirdPartyResourceWatcher, error) {
+// Watch streams changes to a collection of third party resources. newObj is
+// called once per event to produce the value its JSON payload is decoded
+// into, e.g. Watch(ctx, "metrics", ns, func() interface{} { return new(Metric) }).
+func (t *ThirdPartyResources) Watch(ctx context.Context, resource, namespace string, newObj func() interface{}) (*ThirdPartyResourceWatcher, error) {
 	if err := checkResource(t.apiGroup, t.apiVersion, resource, namespace, "name not required"); err != nil {
 		return nil, err
 	}
@@ -186,5 +228,5 @@ func (t *ThirdPartyResources) Watch(ctx context.Context, resource, namespace str
 	if err != nil {
 		return nil, err
 	}
-	return &ThirdPartyResourceWatcher{watcher}, nil
+	return &ThirdPartyResourceWatcher{watcher, newObj}, nil
 }